@@ -0,0 +1,74 @@
+// Command personsd serves the persons HTTP API.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+
+	"github.com/gazizov-ai/lab1-rsoi/pkg/config"
+	"github.com/gazizov-ai/lab1-rsoi/pkg/httpapi"
+	"github.com/gazizov-ai/lab1-rsoi/pkg/store"
+)
+
+func main() {
+	cfg := config.Load()
+
+	db, err := sql.Open("pgx", cfg.DatabaseURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	db.SetMaxOpenConns(10)
+	db.SetMaxIdleConns(5)
+	db.SetConnMaxLifetime(30 * time.Minute)
+
+	if err := db.Ping(); err != nil {
+		log.Fatalf("cannot connect to db: %v", err)
+	}
+
+	if err := store.Migrate(db); err != nil {
+		log.Fatalf("auto-migrate failed: %v", err)
+	}
+
+	health := httpapi.NewHealth()
+	s := store.NewPostgresStore(db)
+	r := httpapi.BuildRouter(s, cfg.AccessLogFormat, cfg.RequestTimeout, health)
+
+	srv := &http.Server{
+		Addr:              cfg.ListenAddr,
+		Handler:           r,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+
+	go func() {
+		log.Printf("listening on %s", cfg.ListenAddr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("listen: %v", err)
+		}
+	}()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	<-stop
+
+	health.StartShutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("graceful shutdown failed: %v", err)
+	}
+
+	db.Close()
+}
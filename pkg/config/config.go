@@ -0,0 +1,66 @@
+// Package config loads personsd's settings from the environment.
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// Config holds the settings personsd needs at startup.
+type Config struct {
+	DatabaseURL     string
+	ListenAddr      string
+	AccessLogFormat string
+
+	// ReadHeaderTimeout, ReadTimeout, WriteTimeout and IdleTimeout configure
+	// the http.Server.
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+
+	// RequestTimeout bounds how long a single request may run before its
+	// context is cancelled.
+	RequestTimeout time.Duration
+
+	// ShutdownTimeout is the grace period given to in-flight requests on
+	// SIGINT/SIGTERM before the server is torn down.
+	ShutdownTimeout time.Duration
+}
+
+// Load reads the environment into a Config, applying defaults for anything
+// left unset.
+func Load() Config {
+	return Config{
+		DatabaseURL:     os.Getenv("DATABASE_URL"),
+		ListenAddr:      envOrDefault("LISTEN_ADDR", ":8080"),
+		AccessLogFormat: os.Getenv("ACCESS_LOG_FORMAT"),
+
+		ReadHeaderTimeout: durationOrDefault("READ_HEADER_TIMEOUT", 5*time.Second),
+		ReadTimeout:       durationOrDefault("READ_TIMEOUT", 10*time.Second),
+		WriteTimeout:      durationOrDefault("WRITE_TIMEOUT", 10*time.Second),
+		IdleTimeout:       durationOrDefault("IDLE_TIMEOUT", 60*time.Second),
+
+		RequestTimeout:  durationOrDefault("REQUEST_TIMEOUT", 5*time.Second),
+		ShutdownTimeout: durationOrDefault("SHUTDOWN_TIMEOUT", 15*time.Second),
+	}
+}
+
+func envOrDefault(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func durationOrDefault(key string, def time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return def
+	}
+	return d
+}
@@ -0,0 +1,35 @@
+package httpapi
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRedactRequestURI(t *testing.T) {
+	token := strings.Repeat("f", 64)
+	got := redactRequestURI("/api/v1/tokens/" + token)
+	if got != "/api/v1/tokens/REDACTED" {
+		t.Fatalf("unexpected redaction result: %q", got)
+	}
+}
+
+func TestAccessLogRedactsTokenShapedPathSegments(t *testing.T) {
+	var buf bytes.Buffer
+	token := strings.Repeat("a", 64)
+	mw := NewAccessLogger(DefaultAccessLogFormat, &buf)
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/api/v1/tokens/"+token, nil))
+
+	if strings.Contains(buf.String(), token) {
+		t.Fatalf("access log leaked the raw token: %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "REDACTED") {
+		t.Fatalf("expected redacted request line, got: %s", buf.String())
+	}
+}
@@ -1,4 +1,6 @@
-package main
+//go:build integration
+
+package httpapi
 
 import (
 	"bytes"
@@ -7,11 +9,14 @@ import (
 	"net/http/httptest"
 	"os"
 	"strconv"
-	"strings"
 	"testing"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+
+	"github.com/gazizov-ai/lab1-rsoi/pkg/store"
 )
 
-func openTestDB(t *testing.T) *sql.DB {
+func openIntegrationDB(t *testing.T) *sql.DB {
 	t.Helper()
 	dsn := os.Getenv("DATABASE_URL")
 	if dsn == "" {
@@ -24,43 +29,38 @@ func openTestDB(t *testing.T) *sql.DB {
 	if err := db.Ping(); err != nil {
 		t.Fatalf("ping db: %v", err)
 	}
+	if err := store.Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
 
 	if _, err := db.Exec(`DELETE FROM persons`); err != nil {
 		t.Fatalf("cleanup: %v", err)
 	}
+	if _, err := db.Exec(`DELETE FROM tokens`); err != nil {
+		t.Fatalf("cleanup: %v", err)
+	}
+	if _, err := db.Exec(`DELETE FROM users`); err != nil {
+		t.Fatalf("cleanup: %v", err)
+	}
 	return db
 }
 
-func newTestServer(t *testing.T, db *sql.DB) http.Handler {
+func newIntegrationServer(t *testing.T, db *sql.DB) http.Handler {
 	t.Helper()
-	r := buildRouter(db)
-	return r
+	return BuildRouter(store.NewPostgresStore(db), "", 0, NewHealth())
 }
 
-func idFromLocation(t *testing.T, w *httptest.ResponseRecorder) int {
-	t.Helper()
-	loc := w.Header().Get("Location")
-	if loc == "" {
-		t.Fatalf("empty location header")
-	}
-	parts := strings.Split(strings.TrimRight(loc, "/"), "/")
-	idStr := parts[len(parts)-1]
-	id, err := strconv.Atoi(idStr)
-	if err != nil || id <= 0 {
-		t.Fatalf("bad location %q", loc)
-	}
-	return id
-}
-
-func TestCreate201_EmptyBody_Location(t *testing.T) {
-	db := openTestDB(t)
+func TestIntegrationCreate201_EmptyBody_Location(t *testing.T) {
+	db := openIntegrationDB(t)
 	defer db.Close()
-	h := newTestServer(t, db)
+	h := newIntegrationServer(t, db)
+	token := newAuthToken(t, h, "alice@example.com")
 
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/persons",
 		bytes.NewBufferString(`{"name":"Alice","age":22}`))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
 	h.ServeHTTP(w, req)
 
 	if w.Code != http.StatusCreated {
@@ -69,33 +69,34 @@ func TestCreate201_EmptyBody_Location(t *testing.T) {
 	if loc := w.Header().Get("Location"); loc == "" {
 		t.Fatalf("Location header is empty")
 	}
-	if w.Body.Len() != 0 {
-		t.Fatalf("body must be empty on 201, got: %q", w.Body.String())
-	}
 }
 
-func TestGet404(t *testing.T) {
-	db := openTestDB(t)
+func TestIntegrationGet404(t *testing.T) {
+	db := openIntegrationDB(t)
 	defer db.Close()
-	h := newTestServer(t, db)
+	h := newIntegrationServer(t, db)
+	token := newAuthToken(t, h, "bob@example.com")
 
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/persons/29042003", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
 	h.ServeHTTP(w, req)
 	if w.Code != http.StatusNotFound {
 		t.Fatalf("expected 404, got %d, body=%s", w.Code, w.Body.String())
 	}
 }
 
-func TestFullFlow(t *testing.T) {
-	db := openTestDB(t)
+func TestIntegrationFullFlow(t *testing.T) {
+	db := openIntegrationDB(t)
 	defer db.Close()
-	h := newTestServer(t, db)
+	h := newIntegrationServer(t, db)
+	token := newAuthToken(t, h, "almas@example.com")
 
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/persons",
 		bytes.NewBufferString(`{"name":"Almas","work":"Student"}`))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
 	h.ServeHTTP(w, req)
 	if w.Code != http.StatusCreated {
 		t.Fatalf("create: expected 201, got %d, body=%s", w.Code, w.Body.String())
@@ -104,6 +105,7 @@ func TestFullFlow(t *testing.T) {
 
 	w = httptest.NewRecorder()
 	req = httptest.NewRequest(http.MethodGet, "/api/v1/persons", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
 	h.ServeHTTP(w, req)
 	if w.Code != http.StatusOK {
 		t.Fatalf("list: expected 200, got %d, body=%s", w.Code, w.Body.String())
@@ -113,6 +115,7 @@ func TestFullFlow(t *testing.T) {
 	req = httptest.NewRequest(http.MethodPatch, "/api/v1/persons/"+strconv.Itoa(id),
 		bytes.NewBufferString(`{"work":"Dev"}`))
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
 	h.ServeHTTP(w, req)
 	if w.Code != http.StatusOK {
 		t.Fatalf("patch: expected 200, got %d, body=%s", w.Code, w.Body.String())
@@ -120,6 +123,7 @@ func TestFullFlow(t *testing.T) {
 
 	w = httptest.NewRecorder()
 	req = httptest.NewRequest(http.MethodDelete, "/api/v1/persons/"+strconv.Itoa(id), nil)
+	req.Header.Set("Authorization", "Bearer "+token)
 	h.ServeHTTP(w, req)
 	if w.Code != http.StatusNoContent {
 		t.Fatalf("delete: expected 204, got %d, body=%s", w.Code, w.Body.String())
@@ -0,0 +1,249 @@
+package httpapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gazizov-ai/lab1-rsoi/pkg/store"
+)
+
+func newTestServer(t *testing.T) http.Handler {
+	t.Helper()
+	return BuildRouter(store.NewMemoryStore(), "", 0, NewHealth())
+}
+
+func TestCreate201_EmptyBody_Location(t *testing.T) {
+	h := newTestServer(t)
+	token := newAuthToken(t, h, "alice@example.com")
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/persons",
+		bytes.NewBufferString(`{"name":"Alice","age":22}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	h.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d, body=%s", w.Code, w.Body.String())
+	}
+	if loc := w.Header().Get("Location"); loc == "" {
+		t.Fatalf("Location header is empty")
+	}
+	if w.Body.Len() != 0 {
+		t.Fatalf("body must be empty on 201, got: %q", w.Body.String())
+	}
+}
+
+func TestGet404(t *testing.T) {
+	h := newTestServer(t)
+	token := newAuthToken(t, h, "bob@example.com")
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/persons/29042003", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d, body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestFullFlow(t *testing.T) {
+	h := newTestServer(t)
+	token := newAuthToken(t, h, "almas@example.com")
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/persons",
+		bytes.NewBufferString(`{"name":"Almas","work":"Student"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create: expected 201, got %d, body=%s", w.Code, w.Body.String())
+	}
+	id := idFromLocation(t, w)
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/persons", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("list: expected 200, got %d, body=%s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPatch, "/api/v1/persons/"+strconv.Itoa(id),
+		bytes.NewBufferString(`{"work":"Dev"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("patch: expected 200, got %d, body=%s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/persons/"+strconv.Itoa(id), nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("delete: expected 204, got %d, body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestPersonsScopedToOwner(t *testing.T) {
+	h := newTestServer(t)
+	tokenA := newAuthToken(t, h, "owner-a@example.com")
+	tokenB := newAuthToken(t, h, "owner-b@example.com")
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/persons",
+		bytes.NewBufferString(`{"name":"Alice"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+tokenA)
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create: expected 201, got %d, body=%s", w.Code, w.Body.String())
+	}
+	id := idFromLocation(t, w)
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/persons/"+strconv.Itoa(id), nil)
+	req.Header.Set("Authorization", "Bearer "+tokenB)
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("get as other user: expected 404, got %d, body=%s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPatch, "/api/v1/persons/"+strconv.Itoa(id),
+		bytes.NewBufferString(`{"name":"Mallory"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+tokenB)
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("patch as other user: expected 404, got %d, body=%s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/persons/"+strconv.Itoa(id), nil)
+	req.Header.Set("Authorization", "Bearer "+tokenB)
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("delete as other user: expected 404, got %d, body=%s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/persons", nil)
+	req.Header.Set("Authorization", "Bearer "+tokenB)
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("list as other user: expected 200, got %d, body=%s", w.Code, w.Body.String())
+	}
+	var page listEnvelope
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatalf("decode list response: %v", err)
+	}
+	if page.Total != 0 || len(page.Items) != 0 {
+		t.Fatalf("expected other user's list to exclude owner's person, got %+v", page)
+	}
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/persons/"+strconv.Itoa(id), nil)
+	req.Header.Set("Authorization", "Bearer "+tokenA)
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("get as owner: expected 200, got %d, body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestHealthzFlipsUnhealthyOnShutdown(t *testing.T) {
+	health := NewHealth()
+	h := BuildRouter(store.NewMemoryStore(), "", 0, health)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 before shutdown, got %d", w.Code)
+	}
+
+	health.StartShutdown()
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 after shutdown, got %d", w.Code)
+	}
+}
+
+func TestListPagingSortingFiltering(t *testing.T) {
+	h := newTestServer(t)
+	token := newAuthToken(t, h, "carol@example.com")
+
+	people := []struct {
+		name string
+		age  int
+		work string
+	}{
+		{"Carol", 40, "Engineer"},
+		{"Ann", 25, "Student"},
+		{"Bob", 30, "Engineer"},
+	}
+	for _, p := range people {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/persons",
+			bytes.NewBufferString(fmt.Sprintf(`{"name":%q,"age":%d,"work":%q}`, p.name, p.age, p.work)))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		h.ServeHTTP(w, req)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("create %s: expected 201, got %d, body=%s", p.name, w.Code, w.Body.String())
+		}
+	}
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/persons?sort=name&limit=2", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("list: expected 200, got %d, body=%s", w.Code, w.Body.String())
+	}
+	var page listEnvelope
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatalf("decode list response: %v", err)
+	}
+	if page.Total != 3 || page.Limit != 2 || page.Offset != 0 {
+		t.Fatalf("unexpected envelope: %+v", page)
+	}
+	if len(page.Items) != 2 || page.Items[0].Name != "Ann" || page.Items[1].Name != "Bob" {
+		t.Fatalf("unexpected sorted page: %+v", page.Items)
+	}
+	if link := w.Header().Get("Link"); link == "" || !bytes.Contains([]byte(link), []byte(`rel="next"`)) {
+		t.Fatalf("expected Link header with rel=next, got %q", link)
+	}
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/persons?work=Engineer&min_age=35", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("filtered list: expected 200, got %d, body=%s", w.Code, w.Body.String())
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatalf("decode filtered list response: %v", err)
+	}
+	if len(page.Items) != 1 || page.Items[0].Name != "Carol" {
+		t.Fatalf("unexpected filtered page: %+v", page.Items)
+	}
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/persons?sort=bogus", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("invalid sort: expected 400, got %d, body=%s", w.Code, w.Body.String())
+	}
+}
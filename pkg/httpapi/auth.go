@@ -0,0 +1,153 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gazizov-ai/lab1-rsoi/pkg/store"
+	"github.com/go-chi/chi/v5"
+)
+
+type ctxKey string
+
+const userIDCtxKey ctxKey = "userID"
+
+type registerUserIn struct {
+	Email string `json:"email"`
+}
+
+type registerUserOut struct {
+	ID    int    `json:"id"`
+	Email string `json:"email"`
+	Token string `json:"token"`
+}
+
+type issueTokenOut struct {
+	Token string `json:"token"`
+}
+
+// requireAuth reads a Bearer token from the Authorization header, looks it
+// up via the AuthStore and, if it is present and not revoked, attaches the
+// owning user's id to the request context. Missing or invalid tokens get a
+// 401 in the existing errorResp shape.
+func requireAuth(s store.AuthStore) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || token == "" {
+				writeErr(w, http.StatusUnauthorized, "missing bearer token")
+				return
+			}
+
+			userID, err := s.UserIDByToken(r.Context(), token)
+			if err != nil {
+				if errors.Is(err, store.ErrNotFound) {
+					writeErr(w, http.StatusUnauthorized, "invalid or revoked token")
+					return
+				}
+				writeErr(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), userIDCtxKey, userID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func userIDFromContext(ctx context.Context) (int, bool) {
+	userID, ok := ctx.Value(userIDCtxKey).(int)
+	return userID, ok
+}
+
+func registerUser(w http.ResponseWriter, r *http.Request, s store.AuthStore) {
+	var in registerUserIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeErr(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	if in.Email == "" {
+		writeValidation(w, "email", "must not be empty")
+		return
+	}
+
+	userID, err := s.RegisterUser(r.Context(), in.Email)
+	if err != nil {
+		if errors.Is(err, store.ErrConflict) {
+			writeErr(w, http.StatusConflict, "email already registered")
+			return
+		}
+		writeErr(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	token, err := s.MintToken(r.Context(), userID)
+	if err != nil {
+		writeErr(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Location", "/api/v1/users/"+strconv.Itoa(userID))
+	writeJSON(w, http.StatusCreated, registerUserOut{ID: userID, Email: in.Email, Token: token})
+}
+
+// issueToken mints an additional token for the already-authenticated caller
+// (e.g. so they can use a second device). It sits behind requireAuth, so
+// knowing a user's email is never enough to obtain a token for their
+// account.
+func issueToken(w http.ResponseWriter, r *http.Request, s store.AuthStore) {
+	userID, ok := userIDFromContext(r.Context())
+	if !ok {
+		writeErr(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	token, err := s.MintToken(r.Context(), userID)
+	if err != nil {
+		writeErr(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, issueTokenOut{Token: token})
+}
+
+// revokeToken sits behind requireAuth and only lets a caller revoke a token
+// that maps to their own user id, so an authenticated user can't revoke
+// another user's token by guessing its value.
+func revokeToken(w http.ResponseWriter, r *http.Request, s store.AuthStore) {
+	userID, ok := userIDFromContext(r.Context())
+	if !ok {
+		writeErr(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	token := chi.URLParam(r, "token")
+	owner, err := s.UserIDByToken(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			writeErr(w, http.StatusNotFound, "not found")
+			return
+		}
+		writeErr(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if owner != userID {
+		writeErr(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	revoked, err := s.RevokeToken(r.Context(), token)
+	if err != nil {
+		writeErr(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !revoked {
+		writeErr(w, http.StatusNotFound, "not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,111 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIssueTokenRequiresAuth(t *testing.T) {
+	h := newTestServer(t)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/tokens", nil)
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d, body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestIssueTokenMintsAdditionalToken(t *testing.T) {
+	h := newTestServer(t)
+	first := newAuthToken(t, h, "issue@example.com")
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/tokens", nil)
+	req.Header.Set("Authorization", "Bearer "+first)
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d, body=%s", w.Code, w.Body.String())
+	}
+
+	var out issueTokenOut
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+		t.Fatalf("decode issue response: %v", err)
+	}
+	if out.Token == "" || out.Token == first {
+		t.Fatalf("expected a fresh, non-empty token, got %q", out.Token)
+	}
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/persons", nil)
+	req.Header.Set("Authorization", "Bearer "+out.Token)
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("new token should authenticate: expected 200, got %d, body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestRevokeTokenRequiresAuth(t *testing.T) {
+	h := newTestServer(t)
+	token := newAuthToken(t, h, "revoke-noauth@example.com")
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/tokens/"+token, nil)
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d, body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestRevokeTokenRejectsOtherUsersToken(t *testing.T) {
+	h := newTestServer(t)
+	tokenA := newAuthToken(t, h, "revoke-a@example.com")
+	tokenB := newAuthToken(t, h, "revoke-b@example.com")
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/tokens/"+tokenA, nil)
+	req.Header.Set("Authorization", "Bearer "+tokenB)
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 revoking another user's token, got %d, body=%s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/persons", nil)
+	req.Header.Set("Authorization", "Bearer "+tokenA)
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("token A should still be valid: expected 200, got %d, body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestRevokeTokenThenRejectsOldToken(t *testing.T) {
+	h := newTestServer(t)
+	token := newAuthToken(t, h, "revoke-self@example.com")
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/tokens/"+token, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d, body=%s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/persons", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("revoked token should be rejected: expected 401, got %d, body=%s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/tokens/"+token, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("revoking with an already-revoked token should fail auth: expected 401, got %d, body=%s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,50 @@
+package httpapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func idFromLocation(t *testing.T, w *httptest.ResponseRecorder) int {
+	t.Helper()
+	loc := w.Header().Get("Location")
+	if loc == "" {
+		t.Fatalf("empty location header")
+	}
+	parts := strings.Split(strings.TrimRight(loc, "/"), "/")
+	idStr := parts[len(parts)-1]
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		t.Fatalf("bad location %q", loc)
+	}
+	return id
+}
+
+// newAuthToken registers a fresh user through the public API and returns a
+// token that can be set on the Authorization header of later requests.
+func newAuthToken(t *testing.T, h http.Handler, email string) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users",
+		bytes.NewBufferString(fmt.Sprintf(`{"email":%q}`, email)))
+	req.Header.Set("Content-Type", "application/json")
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("register user: expected 201, got %d, body=%s", w.Code, w.Body.String())
+	}
+
+	var out registerUserOut
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+		t.Fatalf("decode register response: %v", err)
+	}
+	if out.Token == "" {
+		t.Fatalf("empty token in register response")
+	}
+	return out.Token
+}
@@ -0,0 +1,31 @@
+package httpapi
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// Health backs the /healthz endpoint. It reports healthy until
+// StartShutdown is called, at which point it starts returning 503 so a load
+// balancer can drain the instance while it finishes in-flight requests.
+type Health struct {
+	shuttingDown atomic.Bool
+}
+
+// NewHealth returns a Health that reports healthy.
+func NewHealth() *Health {
+	return &Health{}
+}
+
+// StartShutdown flips the health check to unhealthy.
+func (h *Health) StartShutdown() {
+	h.shuttingDown.Store(true)
+}
+
+func (h *Health) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.shuttingDown.Load() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
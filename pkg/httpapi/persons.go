@@ -0,0 +1,232 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gazizov-ai/lab1-rsoi/pkg/store"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 500
+)
+
+var allowedSortFields = map[string]bool{"id": true, "name": true, "age": true}
+
+type listEnvelope struct {
+	Items  []store.Person `json:"items"`
+	Total  int            `json:"total"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+}
+
+func listPersons(w http.ResponseWriter, r *http.Request, s store.PersonStore) {
+	userID, ok := userIDFromContext(r.Context())
+	if !ok {
+		writeErr(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	params, ok := parseListParams(w, r)
+	if !ok {
+		return
+	}
+
+	res, err := s.List(r.Context(), userID, params)
+	if err != nil {
+		writeStoreErr(w, err)
+		return
+	}
+
+	setListLinkHeader(w, r, params, res.Total)
+	writeJSON(w, http.StatusOK, listEnvelope{
+		Items:  res.Items,
+		Total:  res.Total,
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	})
+}
+
+// parseListParams reads limit/offset/sort/name/min_age/max_age/work from the
+// query string, writing a 400 via writeValidation and returning ok=false on
+// the first invalid value.
+func parseListParams(w http.ResponseWriter, r *http.Request) (store.ListParams, bool) {
+	q := r.URL.Query()
+
+	limit := defaultLimit
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeValidation(w, "limit", "must be a positive integer")
+			return store.ListParams{}, false
+		}
+		limit = n
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	offset := 0
+	if v := q.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeValidation(w, "offset", "must be a non-negative integer")
+			return store.ListParams{}, false
+		}
+		offset = n
+	}
+
+	sortBy, sortDesc := "id", false
+	if v := q.Get("sort"); v != "" {
+		field := strings.TrimPrefix(v, "-")
+		if !allowedSortFields[field] {
+			writeValidation(w, "sort", "must be one of id, name, age, optionally prefixed with -")
+			return store.ListParams{}, false
+		}
+		sortBy, sortDesc = field, strings.HasPrefix(v, "-")
+	}
+
+	params := store.ListParams{
+		Limit:    limit,
+		Offset:   offset,
+		SortBy:   sortBy,
+		SortDesc: sortDesc,
+		Name:     q.Get("name"),
+		Work:     q.Get("work"),
+	}
+
+	if v := q.Get("min_age"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			writeValidation(w, "min_age", "must be an integer")
+			return store.ListParams{}, false
+		}
+		params.MinAge = &n
+	}
+	if v := q.Get("max_age"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			writeValidation(w, "max_age", "must be an integer")
+			return store.ListParams{}, false
+		}
+		params.MaxAge = &n
+	}
+
+	return params, true
+}
+
+// setListLinkHeader emits an RFC 5988 Link header with rel="next"/rel="prev"
+// page URIs, built by re-encoding the current query with a shifted offset.
+func setListLinkHeader(w http.ResponseWriter, r *http.Request, p store.ListParams, total int) {
+	var links []string
+	if p.Offset+p.Limit < total {
+		links = append(links, `<`+pageURL(r, p.Offset+p.Limit, p.Limit)+`>; rel="next"`)
+	}
+	if p.Offset > 0 {
+		prevOffset := p.Offset - p.Limit
+		if prevOffset < 0 {
+			prevOffset = 0
+		}
+		links = append(links, `<`+pageURL(r, prevOffset, p.Limit)+`>; rel="prev"`)
+	}
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+}
+
+func pageURL(r *http.Request, offset, limit int) string {
+	q := r.URL.Query()
+	q.Set("offset", strconv.Itoa(offset))
+	q.Set("limit", strconv.Itoa(limit))
+	u := *r.URL
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+func getPerson(w http.ResponseWriter, r *http.Request, s store.PersonStore) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromContext(r.Context())
+	if !ok {
+		writeErr(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	p, err := s.Get(r.Context(), userID, id)
+	if err != nil {
+		writeStoreErr(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, p)
+}
+
+func createPerson(w http.ResponseWriter, r *http.Request, s store.PersonStore) {
+	userID, ok := userIDFromContext(r.Context())
+	if !ok {
+		writeErr(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	var in store.PersonIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeErr(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	if in.Name == "" {
+		writeValidation(w, "name", "must not be empty")
+		return
+	}
+
+	p, err := s.Create(r.Context(), userID, in)
+	if err != nil {
+		writeStoreErr(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/api/v1/persons/"+strconv.Itoa(p.ID))
+	w.WriteHeader(http.StatusCreated)
+}
+
+func patchPerson(w http.ResponseWriter, r *http.Request, s store.PersonStore) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromContext(r.Context())
+	if !ok {
+		writeErr(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	var in store.PersonIn
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		writeErr(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	p, err := s.Patch(r.Context(), userID, id, in)
+	if err != nil {
+		writeStoreErr(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, p)
+}
+
+func deletePerson(w http.ResponseWriter, r *http.Request, s store.PersonStore) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := userIDFromContext(r.Context())
+	if !ok {
+		writeErr(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if err := s.Delete(r.Context(), userID, id); err != nil {
+		writeStoreErr(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,55 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gazizov-ai/lab1-rsoi/pkg/store"
+	"github.com/go-chi/chi/v5"
+)
+
+type errorResp struct {
+	Message string            `json:"message"`
+	Errors  map[string]string `json:"errors,omitempty"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeErr(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, errorResp{Message: msg})
+}
+
+func writeValidation(w http.ResponseWriter, field, msg string) {
+	writeJSON(w, http.StatusBadRequest, errorResp{
+		Message: "validation failed",
+		Errors:  map[string]string{field: msg},
+	})
+}
+
+func parseID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil || id <= 0 {
+		writeErr(w, http.StatusBadRequest, "invalid id")
+		return 0, false
+	}
+	return id, true
+}
+
+func writeStoreErr(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		writeErr(w, http.StatusNotFound, "not found")
+	case errors.Is(err, context.DeadlineExceeded):
+		writeErr(w, http.StatusGatewayTimeout, "request timed out")
+	default:
+		writeErr(w, http.StatusInternalServerError, err.Error())
+	}
+}
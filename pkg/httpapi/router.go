@@ -0,0 +1,58 @@
+// Package httpapi wires the persons/users/tokens HTTP handlers on top of a
+// store.Store, independent of whether that store is backed by Postgres or
+// an in-memory stand-in.
+package httpapi
+
+import (
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gazizov-ai/lab1-rsoi/pkg/store"
+	"github.com/go-chi/chi/v5"
+)
+
+// defaultRequestTimeout is used when BuildRouter is called with a
+// non-positive requestTimeout (e.g. from tests that don't care).
+const defaultRequestTimeout = 5 * time.Second
+
+// BuildRouter assembles the full HTTP API: the access log and per-request
+// timeout middleware, /healthz, the unauthenticated user registration
+// endpoint, and the tokens/persons endpoints guarded by requireAuth.
+// accessLogFormat falls back to DefaultAccessLogFormat when empty.
+func BuildRouter(s store.Store, accessLogFormat string, requestTimeout time.Duration, health *Health) http.Handler {
+	if accessLogFormat == "" {
+		accessLogFormat = DefaultAccessLogFormat
+	}
+	if requestTimeout <= 0 {
+		requestTimeout = defaultRequestTimeout
+	}
+
+	r := chi.NewRouter()
+	r.Use(NewAccessLogger(accessLogFormat, os.Stdout))
+	r.Use(withRequestTimeout(requestTimeout))
+
+	r.Get("/healthz", health.ServeHTTP)
+
+	r.Route("/api/v1/users", func(r chi.Router) {
+		r.Post("/", func(w http.ResponseWriter, r *http.Request) { registerUser(w, r, s) })
+	})
+	r.Route("/api/v1/tokens", func(r chi.Router) {
+		r.Use(requireAuth(s))
+		r.Post("/", func(w http.ResponseWriter, r *http.Request) { issueToken(w, r, s) })
+		r.Delete("/{token}", func(w http.ResponseWriter, r *http.Request) { revokeToken(w, r, s) })
+	})
+
+	r.Route("/api/v1/persons", func(r chi.Router) {
+		r.Use(requireAuth(s))
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) { listPersons(w, r, s) })
+		r.Post("/", func(w http.ResponseWriter, r *http.Request) { createPerson(w, r, s) })
+		r.Route("/{id}", func(r chi.Router) {
+			r.Get("/", func(w http.ResponseWriter, r *http.Request) { getPerson(w, r, s) })
+			r.Patch("/", func(w http.ResponseWriter, r *http.Request) { patchPerson(w, r, s) })
+			r.Delete("/", func(w http.ResponseWriter, r *http.Request) { deletePerson(w, r, s) })
+		})
+	})
+
+	return r
+}
@@ -0,0 +1,20 @@
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// withRequestTimeout installs a context.WithTimeout on every request so a
+// slow downstream call (e.g. a Postgres query) is cancelled once the
+// deadline fires or the client disconnects.
+func withRequestTimeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
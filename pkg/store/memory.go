@@ -0,0 +1,219 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type tokenRecord struct {
+	userID  int
+	revoked bool
+}
+
+// MemoryStore is an in-memory Store, used by tests and local development so
+// they don't depend on a live Postgres instance.
+type MemoryStore struct {
+	mu      sync.Mutex
+	nextID  int
+	persons map[int]Person
+	owners  map[int]int // person id -> owner user id
+
+	nextUserID int
+	emails     map[string]int // email -> user id
+
+	tokens map[string]tokenRecord
+}
+
+// NewMemoryStore returns an empty MemoryStore ready for use.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		persons: make(map[int]Person),
+		owners:  make(map[int]int),
+		emails:  make(map[string]int),
+		tokens:  make(map[string]tokenRecord),
+	}
+}
+
+func (s *MemoryStore) List(ctx context.Context, ownerID int, p ListParams) (ListResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var matched []Person
+	for id, owner := range s.owners {
+		if owner != ownerID {
+			continue
+		}
+		person := s.persons[id]
+		if p.Name != "" && !strings.Contains(strings.ToLower(person.Name), strings.ToLower(p.Name)) {
+			continue
+		}
+		if p.MinAge != nil && (person.Age == nil || *person.Age < *p.MinAge) {
+			continue
+		}
+		if p.MaxAge != nil && (person.Age == nil || *person.Age > *p.MaxAge) {
+			continue
+		}
+		if p.Work != "" && person.Work != p.Work {
+			continue
+		}
+		matched = append(matched, person)
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		if p.SortDesc {
+			return personLess(matched[j], matched[i], p.SortBy)
+		}
+		return personLess(matched[i], matched[j], p.SortBy)
+	})
+
+	total := len(matched)
+	start := p.Offset
+	if start > total {
+		start = total
+	}
+	end := start + p.Limit
+	if p.Limit <= 0 || end > total {
+		end = total
+	}
+
+	return ListResult{Items: append([]Person{}, matched[start:end]...), Total: total}, nil
+}
+
+func personLess(a, b Person, sortBy string) bool {
+	switch sortBy {
+	case "name":
+		return a.Name < b.Name
+	case "age":
+		return ageOrZero(a.Age) < ageOrZero(b.Age)
+	default:
+		return a.ID < b.ID
+	}
+}
+
+func ageOrZero(age *int) int {
+	if age == nil {
+		return 0
+	}
+	return *age
+}
+
+func (s *MemoryStore) Get(ctx context.Context, ownerID, id int) (Person, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.persons[id]
+	if !ok || s.owners[id] != ownerID {
+		return Person{}, ErrNotFound
+	}
+	return p, nil
+}
+
+func (s *MemoryStore) Create(ctx context.Context, ownerID int, in PersonIn) (Person, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID++
+	p := Person{ID: s.nextID, Name: in.Name, Age: in.Age, Address: in.Address, Work: in.Work}
+	s.persons[p.ID] = p
+	s.owners[p.ID] = ownerID
+	return p, nil
+}
+
+func (s *MemoryStore) Patch(ctx context.Context, ownerID, id int, in PersonIn) (Person, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cur, ok := s.persons[id]
+	if !ok || s.owners[id] != ownerID {
+		return Person{}, ErrNotFound
+	}
+
+	if in.Name != "" {
+		cur.Name = in.Name
+	}
+	if in.Age != nil {
+		cur.Age = in.Age
+	}
+	if in.Address != "" {
+		cur.Address = in.Address
+	}
+	if in.Work != "" {
+		cur.Work = in.Work
+	}
+
+	s.persons[id] = cur
+	return cur, nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, ownerID, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.owners[id] != ownerID {
+		return ErrNotFound
+	}
+	delete(s.persons, id)
+	delete(s.owners, id)
+	return nil
+}
+
+func (s *MemoryStore) RegisterUser(ctx context.Context, email string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.emails[email]; exists {
+		return 0, ErrConflict
+	}
+	s.nextUserID++
+	s.emails[email] = s.nextUserID
+	return s.nextUserID, nil
+}
+
+func (s *MemoryStore) MintToken(ctx context.Context, userID int) (string, error) {
+	token, err := genToken()
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[token] = tokenRecord{userID: userID}
+	return token, nil
+}
+
+func (s *MemoryStore) UserIDByEmail(ctx context.Context, email string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, ok := s.emails[email]
+	if !ok {
+		return 0, ErrNotFound
+	}
+	return id, nil
+}
+
+func (s *MemoryStore) UserIDByToken(ctx context.Context, token string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.tokens[token]
+	if !ok || rec.revoked {
+		return 0, ErrNotFound
+	}
+	return rec.userID, nil
+}
+
+func (s *MemoryStore) RevokeToken(ctx context.Context, token string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.tokens[token]
+	if !ok || rec.revoked {
+		return false, nil
+	}
+	rec.revoked = true
+	s.tokens[token] = rec
+	return true, nil
+}
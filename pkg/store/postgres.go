@@ -0,0 +1,274 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// pgUniqueViolation is the Postgres error code for a unique constraint
+// violation (e.g. a duplicate email).
+const pgUniqueViolation = "23505"
+
+// postgresStore is a PersonStore/AuthStore backed by the pgx/stdlib driver.
+type postgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore wraps an already-opened *sql.DB as a Store.
+func NewPostgresStore(db *sql.DB) *postgresStore {
+	return &postgresStore{db: db}
+}
+
+// Migrate creates the tables this store needs if they don't already exist.
+func Migrate(db *sql.DB) error {
+	if _, err := db.Exec(`
+	CREATE TABLE IF NOT EXISTS users (
+	id SERIAL PRIMARY KEY,
+	email TEXT NOT NULL UNIQUE,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`); err != nil {
+		return err
+	}
+
+	if _, err := db.Exec(`
+	CREATE TABLE IF NOT EXISTS tokens (
+	token TEXT PRIMARY KEY,
+	user_id INTEGER NOT NULL REFERENCES users(id),
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	revoked_at TIMESTAMPTZ
+	)`); err != nil {
+		return err
+	}
+
+	if _, err := db.Exec(`
+	CREATE TABLE IF NOT EXISTS persons (
+	id SERIAL PRIMARY KEY,
+	name TEXT NOT NULL,
+	age INTEGER,
+	address TEXT,
+	work TEXT,
+	owner_id INTEGER REFERENCES users(id)
+	)`); err != nil {
+		return err
+	}
+
+	if _, err := db.Exec(`ALTER TABLE persons ADD COLUMN IF NOT EXISTS owner_id INTEGER REFERENCES users(id)`); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// sortColumns whitelists the columns ListParams.SortBy may map to, so a sort
+// value can never be interpolated straight into the ORDER BY clause.
+var sortColumns = map[string]string{
+	"id":   "id",
+	"name": "name",
+	"age":  "age",
+}
+
+func (s *postgresStore) List(ctx context.Context, ownerID int, p ListParams) (ListResult, error) {
+	col, ok := sortColumns[p.SortBy]
+	if !ok {
+		col = "id"
+	}
+	orderBy := col
+	if p.SortDesc {
+		orderBy += " DESC"
+	}
+
+	where := "owner_id=$1"
+	args := []any{ownerID}
+	if p.Name != "" {
+		args = append(args, "%"+p.Name+"%")
+		where += fmt.Sprintf(" AND name ILIKE $%d", len(args))
+	}
+	if p.MinAge != nil {
+		args = append(args, *p.MinAge)
+		where += fmt.Sprintf(" AND age >= $%d", len(args))
+	}
+	if p.MaxAge != nil {
+		args = append(args, *p.MaxAge)
+		where += fmt.Sprintf(" AND age <= $%d", len(args))
+	}
+	if p.Work != "" {
+		args = append(args, p.Work)
+		where += fmt.Sprintf(" AND work=$%d", len(args))
+	}
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM persons WHERE "+where, args...).Scan(&total); err != nil {
+		return ListResult{}, err
+	}
+
+	selectArgs := append(append([]any{}, args...), p.Limit, p.Offset)
+	query := fmt.Sprintf(
+		"SELECT id, name, age, address, work FROM persons WHERE %s ORDER BY %s LIMIT $%d OFFSET $%d",
+		where, orderBy, len(selectArgs)-1, len(selectArgs),
+	)
+	rows, err := s.db.QueryContext(ctx, query, selectArgs...)
+	if err != nil {
+		return ListResult{}, err
+	}
+	defer rows.Close()
+
+	var items []Person
+	for rows.Next() {
+		var p Person
+		var age sql.NullInt32
+		if err := rows.Scan(&p.ID, &p.Name, &age, &p.Address, &p.Work); err != nil {
+			return ListResult{}, err
+		}
+		if age.Valid {
+			a := int(age.Int32)
+			p.Age = &a
+		}
+		items = append(items, p)
+	}
+	if err := rows.Err(); err != nil {
+		return ListResult{}, err
+	}
+
+	return ListResult{Items: items, Total: total}, nil
+}
+
+func (s *postgresStore) Get(ctx context.Context, ownerID, id int) (Person, error) {
+	var p Person
+	var age sql.NullInt32
+	err := s.db.QueryRowContext(ctx,
+		`SELECT id, name, age, address, work FROM persons WHERE id=$1 AND owner_id=$2`, id, ownerID,
+	).Scan(&p.ID, &p.Name, &age, &p.Address, &p.Work)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Person{}, ErrNotFound
+		}
+		return Person{}, err
+	}
+	if age.Valid {
+		a := int(age.Int32)
+		p.Age = &a
+	}
+	return p, nil
+}
+
+func (s *postgresStore) Create(ctx context.Context, ownerID int, in PersonIn) (Person, error) {
+	p := Person{Name: in.Name, Age: in.Age, Address: in.Address, Work: in.Work}
+	err := s.db.QueryRowContext(ctx,
+		`INSERT INTO persons (name, age, address, work, owner_id) VALUES ($1,$2,$3,$4,$5) RETURNING id`,
+		in.Name, in.Age, in.Address, in.Work, ownerID,
+	).Scan(&p.ID)
+	if err != nil {
+		return Person{}, err
+	}
+	return p, nil
+}
+
+func (s *postgresStore) Patch(ctx context.Context, ownerID, id int, in PersonIn) (Person, error) {
+	cur, err := s.Get(ctx, ownerID, id)
+	if err != nil {
+		return Person{}, err
+	}
+
+	if in.Name != "" {
+		cur.Name = in.Name
+	}
+	if in.Age != nil {
+		cur.Age = in.Age
+	}
+	if in.Address != "" {
+		cur.Address = in.Address
+	}
+	if in.Work != "" {
+		cur.Work = in.Work
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		`UPDATE persons SET name=$1, age=$2, address=$3, work=$4 WHERE id=$5 AND owner_id=$6`,
+		cur.Name, cur.Age, cur.Address, cur.Work, id, ownerID,
+	)
+	if err != nil {
+		return Person{}, err
+	}
+	return cur, nil
+}
+
+func (s *postgresStore) Delete(ctx context.Context, ownerID, id int) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM persons WHERE id=$1 AND owner_id=$2`, id, ownerID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *postgresStore) RegisterUser(ctx context.Context, email string) (int, error) {
+	var userID int
+	err := s.db.QueryRowContext(ctx, `INSERT INTO users (email) VALUES ($1) RETURNING id`, email).Scan(&userID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+			return 0, ErrConflict
+		}
+		return 0, err
+	}
+	return userID, nil
+}
+
+func (s *postgresStore) MintToken(ctx context.Context, userID int) (string, error) {
+	token, err := genToken()
+	if err != nil {
+		return "", err
+	}
+	if _, err := s.db.ExecContext(ctx, `INSERT INTO tokens (token, user_id) VALUES ($1, $2)`, token, userID); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *postgresStore) UserIDByEmail(ctx context.Context, email string) (int, error) {
+	var userID int
+	err := s.db.QueryRowContext(ctx, `SELECT id FROM users WHERE email=$1`, email).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrNotFound
+		}
+		return 0, err
+	}
+	return userID, nil
+}
+
+func (s *postgresStore) UserIDByToken(ctx context.Context, token string) (int, error) {
+	var userID int
+	err := s.db.QueryRowContext(ctx,
+		`SELECT user_id FROM tokens WHERE token=$1 AND revoked_at IS NULL`, token,
+	).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrNotFound
+		}
+		return 0, err
+	}
+	return userID, nil
+}
+
+func (s *postgresStore) RevokeToken(ctx context.Context, token string) (bool, error) {
+	res, err := s.db.ExecContext(ctx, `UPDATE tokens SET revoked_at = now() WHERE token=$1 AND revoked_at IS NULL`, token)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
@@ -0,0 +1,90 @@
+// Package store defines the persistence contracts the persons API depends
+// on, plus a Postgres-backed implementation and an in-memory one for tests.
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+)
+
+// ErrNotFound is returned by store methods when no matching row exists for
+// the given (and, where relevant, owner-scoped) lookup.
+var ErrNotFound = errors.New("not found")
+
+// ErrConflict is returned when an operation would violate a uniqueness
+// constraint, e.g. registering an email that is already taken.
+var ErrConflict = errors.New("conflict")
+
+// Person is a single person record, scoped to the user that owns it.
+type Person struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Age     *int   `json:"age,omitempty"`
+	Address string `json:"address,omitempty"`
+	Work    string `json:"work,omitempty"`
+}
+
+// PersonIn is the subset of Person fields a client may set on create/patch.
+type PersonIn struct {
+	Name    string `json:"name"`
+	Age     *int   `json:"age,omitempty"`
+	Address string `json:"address,omitempty"`
+	Work    string `json:"work,omitempty"`
+}
+
+// ListParams controls pagination, filtering and sorting for
+// PersonStore.List. SortBy must already be validated against the supported
+// sort fields ("id", "name", "age") before being passed in.
+type ListParams struct {
+	Limit    int
+	Offset   int
+	SortBy   string
+	SortDesc bool
+	Name     string // ILIKE substring filter
+	MinAge   *int
+	MaxAge   *int
+	Work     string // exact match
+}
+
+// ListResult is a page of persons plus the total row count matching the
+// filters, ignoring Limit/Offset.
+type ListResult struct {
+	Items []Person
+	Total int
+}
+
+// PersonStore is the storage contract the persons API depends on. Every
+// method is scoped to ownerID so callers only ever see their own rows.
+type PersonStore interface {
+	List(ctx context.Context, ownerID int, params ListParams) (ListResult, error)
+	Get(ctx context.Context, ownerID, id int) (Person, error)
+	Create(ctx context.Context, ownerID int, in PersonIn) (Person, error)
+	Patch(ctx context.Context, ownerID, id int, in PersonIn) (Person, error)
+	Delete(ctx context.Context, ownerID, id int) error
+}
+
+// AuthStore is the storage contract the users/tokens endpoints depend on.
+type AuthStore interface {
+	RegisterUser(ctx context.Context, email string) (int, error)
+	MintToken(ctx context.Context, userID int) (string, error)
+	UserIDByEmail(ctx context.Context, email string) (int, error)
+	UserIDByToken(ctx context.Context, token string) (int, error)
+	RevokeToken(ctx context.Context, token string) (bool, error)
+}
+
+// Store is the full contract BuildRouter depends on.
+type Store interface {
+	PersonStore
+	AuthStore
+}
+
+// genToken returns a cryptographically random, hex-encoded token.
+func genToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}